@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/vexfs/vexfs/deployment/kubernetes/csi/pkg/driver"
+	"github.com/vexfs/vexfs/deployment/kubernetes/csi/pkg/driver/node"
 	"k8s.io/klog/v2"
 )
 
@@ -16,10 +17,14 @@ var (
 
 func main() {
 	var (
-		endpoint   = flag.String("endpoint", "unix://tmp/csi.sock", "CSI endpoint")
-		nodeID     = flag.String("nodeid", "", "node id")
-		showVer    = flag.Bool("version", false, "Show version")
-		maxVolumes = flag.Int64("max-volumes-per-node", 0, "limit of volumes per node")
+		endpoint     = flag.String("endpoint", "unix://tmp/csi.sock", "CSI endpoint")
+		httpEndpoint = flag.String("http-endpoint", "", "address to serve /metrics and /healthz on, e.g. :9808 (disabled if empty)")
+		nodeID       = flag.String("nodeid", "", "node id")
+		mode         = flag.String("mode", string(driver.ModeAll), "driver mode: all, controller, or node")
+		ephemeral    = flag.Bool("ephemeral", false, "enable CSI ephemeral inline volume support")
+		topologyKeys = flag.String("topology-keys", "", "comma-separated topology keys to report via NodeGetInfo (see node.ValidTopologyKeys)")
+		showVer      = flag.Bool("version", false, "Show version")
+		maxVolumes   = flag.Int64("max-volumes-per-node", 0, "limit of volumes per node")
 	)
 	klog.InitFlags(nil)
 	flag.Parse()
@@ -31,19 +36,25 @@ func main() {
 		os.Exit(0)
 	}
 
-	if *nodeID == "" {
+	driverMode := driver.Mode(*mode)
+	if driverMode != driver.ModeController && *nodeID == "" {
 		klog.Error("nodeid must be provided")
 		os.Exit(1)
 	}
 
-	klog.Infof("Starting VexFS CSI Driver, version: %s, driver: %s", version, driverName)
+	keys, err := node.ParseTopologyKeys(*topologyKeys)
+	if err != nil {
+		klog.Fatalf("Invalid --topology-keys: %v", err)
+	}
+
+	klog.Infof("Starting VexFS CSI Driver, version: %s, driver: %s, mode: %s", version, driverName, driverMode)
 
-	d, err := driver.NewDriver(driverName, version, *nodeID, *maxVolumes)
+	d, err := driver.NewDriver(driverMode, driverName, version, *nodeID, *maxVolumes, *ephemeral, keys)
 	if err != nil {
 		klog.Fatalf("Failed to initialize driver: %v", err)
 	}
 
-	if err := d.Run(*endpoint); err != nil {
+	if err := d.Run(*endpoint, *httpEndpoint); err != nil {
 		klog.Fatalf("Failed to run driver: %v", err)
 	}
 }
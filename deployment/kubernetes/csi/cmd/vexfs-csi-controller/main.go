@@ -0,0 +1,46 @@
+// Command vexfs-csi-controller runs the VexFS CSI driver's Controller and
+// Identity services only, for deployment as a Kubernetes Deployment.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/vexfs/vexfs/deployment/kubernetes/csi/pkg/driver"
+	"k8s.io/klog/v2"
+)
+
+var (
+	version    = "1.0.0"
+	driverName = "vexfs.csi.k8s.io"
+)
+
+func main() {
+	var (
+		endpoint     = flag.String("endpoint", "unix://tmp/csi.sock", "CSI endpoint")
+		httpEndpoint = flag.String("http-endpoint", "", "address to serve /metrics and /healthz on, e.g. :9808 (disabled if empty)")
+		showVer      = flag.Bool("version", false, "Show version")
+		maxVolumes   = flag.Int64("max-volumes-per-node", 0, "limit of volumes per node")
+	)
+	klog.InitFlags(nil)
+	flag.Parse()
+
+	if *showVer {
+		fmt.Printf("VexFS CSI Controller\n")
+		fmt.Printf("Version: %s\n", version)
+		fmt.Printf("Driver Name: %s\n", driverName)
+		os.Exit(0)
+	}
+
+	klog.Infof("Starting VexFS CSI Controller, version: %s, driver: %s", version, driverName)
+
+	d, err := driver.NewDriver(driver.ModeController, driverName, version, "", *maxVolumes, false, nil)
+	if err != nil {
+		klog.Fatalf("Failed to initialize driver: %v", err)
+	}
+
+	if err := d.Run(*endpoint, *httpEndpoint); err != nil {
+		klog.Fatalf("Failed to run driver: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+// Command vexfs-csi-node runs the VexFS CSI driver's Node and Identity
+// services only, for deployment as a Kubernetes DaemonSet.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/vexfs/vexfs/deployment/kubernetes/csi/pkg/driver"
+	"github.com/vexfs/vexfs/deployment/kubernetes/csi/pkg/driver/node"
+	"k8s.io/klog/v2"
+)
+
+var (
+	version    = "1.0.0"
+	driverName = "vexfs.csi.k8s.io"
+)
+
+func main() {
+	var (
+		endpoint     = flag.String("endpoint", "unix://tmp/csi.sock", "CSI endpoint")
+		httpEndpoint = flag.String("http-endpoint", "", "address to serve /metrics and /healthz on, e.g. :9808 (disabled if empty)")
+		nodeID       = flag.String("nodeid", "", "node id")
+		ephemeral    = flag.Bool("ephemeral", false, "enable CSI ephemeral inline volume support")
+		topologyKeys = flag.String("topology-keys", "", "comma-separated topology keys to report via NodeGetInfo (see node.ValidTopologyKeys)")
+		showVer      = flag.Bool("version", false, "Show version")
+		maxVolumes   = flag.Int64("max-volumes-per-node", 0, "limit of volumes per node")
+	)
+	klog.InitFlags(nil)
+	flag.Parse()
+
+	if *showVer {
+		fmt.Printf("VexFS CSI Node\n")
+		fmt.Printf("Version: %s\n", version)
+		fmt.Printf("Driver Name: %s\n", driverName)
+		os.Exit(0)
+	}
+
+	if *nodeID == "" {
+		klog.Error("nodeid must be provided")
+		os.Exit(1)
+	}
+
+	keys, err := node.ParseTopologyKeys(*topologyKeys)
+	if err != nil {
+		klog.Fatalf("Invalid --topology-keys: %v", err)
+	}
+
+	klog.Infof("Starting VexFS CSI Node, version: %s, driver: %s", version, driverName)
+
+	d, err := driver.NewDriver(driver.ModeNode, driverName, version, *nodeID, *maxVolumes, *ephemeral, keys)
+	if err != nil {
+		klog.Fatalf("Failed to initialize driver: %v", err)
+	}
+
+	if err := d.Run(*endpoint, *httpEndpoint); err != nil {
+		klog.Fatalf("Failed to run driver: %v", err)
+	}
+}
@@ -0,0 +1,596 @@
+// Package controller implements the CSI Controller service for VexFS.
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+
+	"github.com/vexfs/vexfs/deployment/kubernetes/csi/pkg/driver/volumestore"
+)
+
+const (
+	// VexFS volume parameters
+	VexFSVolumeSize = "vexfs.volume.size"
+	VexFSVectorDim  = "vexfs.vector.dimension"
+	VexFSIndexType  = "vexfs.index.type"
+)
+
+// Controller implements csi.ControllerServer for VexFS volumes.
+type Controller struct {
+	maxVolumes int64
+
+	cap   []*csi.VolumeCapability_AccessMode
+	cscap []*csi.ControllerServiceCapability
+}
+
+// New creates a Controller service.
+func New(maxVolumes int64) *Controller {
+	c := &Controller{maxVolumes: maxVolumes}
+
+	c.addVolumeCapabilityAccessModes([]csi.VolumeCapability_AccessMode_Mode{
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY,
+		csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER,
+	})
+
+	c.addControllerServiceCapabilities([]csi.ControllerServiceCapability_RPC_Type{
+		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
+		csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
+		csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
+	})
+
+	return c
+}
+
+func (c *Controller) addVolumeCapabilityAccessModes(modes []csi.VolumeCapability_AccessMode_Mode) {
+	var vca []*csi.VolumeCapability_AccessMode
+	for _, m := range modes {
+		klog.Infof("Enabling volume access mode: %v", m.String())
+		vca = append(vca, &csi.VolumeCapability_AccessMode{Mode: m})
+	}
+	c.cap = vca
+}
+
+func (c *Controller) addControllerServiceCapabilities(rpcs []csi.ControllerServiceCapability_RPC_Type) {
+	var csc []*csi.ControllerServiceCapability
+	for _, rpc := range rpcs {
+		klog.Infof("Enabling controller service capability: %v", rpc.String())
+		csc = append(csc, &csi.ControllerServiceCapability{
+			Type: &csi.ControllerServiceCapability_Rpc{
+				Rpc: &csi.ControllerServiceCapability_RPC{
+					Type: rpc,
+				},
+			},
+		})
+	}
+	c.cscap = csc
+}
+
+// validateVolumeCapabilities reports whether every requested capability is
+// supported by this Controller.
+func (c *Controller) validateVolumeCapabilities(volumeCaps []*csi.VolumeCapability) error {
+	for _, volCap := range volumeCaps {
+		if volCap.GetAccessMode() == nil {
+			return fmt.Errorf("volume capability is missing an access mode")
+		}
+		supported := false
+		for _, m := range c.cap {
+			if m.GetMode() == volCap.GetAccessMode().GetMode() {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return fmt.Errorf("unsupported access mode %v", volCap.GetAccessMode().GetMode())
+		}
+	}
+	return nil
+}
+
+// CreateVolume creates a new VexFS volume
+func (c *Controller) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest) (*csi.CreateVolumeResponse, error) {
+	if len(req.GetName()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume name missing in request")
+	}
+
+	if req.GetVolumeCapabilities() == nil {
+		return nil, status.Error(codes.InvalidArgument, "Volume capabilities missing in request")
+	}
+
+	// Validate volume capabilities
+	if err := c.validateVolumeCapabilities(req.GetVolumeCapabilities()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	volumeID := req.GetName()
+	size := int64(1 * 1024 * 1024 * 1024) // Default 1GB
+
+	// Parse capacity range
+	if req.GetCapacityRange() != nil {
+		if req.GetCapacityRange().GetRequiredBytes() > 0 {
+			size = req.GetCapacityRange().GetRequiredBytes()
+		}
+	}
+
+	// Parse VexFS-specific parameters
+	parameters := req.GetParameters()
+	if parameters != nil {
+		if sizeStr, ok := parameters[VexFSVolumeSize]; ok {
+			if parsedSize, err := strconv.ParseInt(sizeStr, 10, 64); err == nil {
+				size = parsedSize
+			}
+		}
+	}
+
+	klog.Infof("Creating VexFS volume %s with size %d bytes", volumeID, size)
+
+	volumePath := filepath.Join(volumesDir, volumeID)
+	blockMode := hasBlockAccessType(req.GetVolumeCapabilities())
+
+	switch {
+	case req.GetVolumeContentSource() != nil && req.GetVolumeContentSource().GetSnapshot() != nil:
+		snapshotID := req.GetVolumeContentSource().GetSnapshot().GetSnapshotId()
+		meta, err := loadSnapshotMetadata(snapshotID)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, status.Errorf(codes.NotFound, "Source snapshot %s does not exist", snapshotID)
+			}
+			return nil, status.Errorf(codes.Internal, "Failed to read source snapshot %s: %v", snapshotID, err)
+		}
+
+		klog.Infof("Restoring VexFS volume %s from snapshot %s", volumeID, snapshotID)
+
+		if _, err := copyVolumeTree(snapshotPath(snapshotID), volumePath); err != nil {
+			os.RemoveAll(volumePath)
+			return nil, status.Errorf(codes.Internal, "Failed to restore volume %s from snapshot %s: %v", volumeID, snapshotID, err)
+		}
+
+		if size < meta.SizeBytes {
+			size = meta.SizeBytes
+		}
+
+		if blockMode {
+			// The snapshot may be smaller than the requested capacity
+			// (CapacityRange.RequiredBytes is allowed to exceed the source
+			// snapshot's size); grow the restored block.img to match so
+			// CapacityBytes/vexfs.meta's size= doesn't outrun reality.
+			if err := os.Truncate(filepath.Join(volumePath, blockImageName), size); err != nil {
+				os.RemoveAll(volumePath)
+				return nil, status.Errorf(codes.Internal, "Failed to resize restored block volume %s: %v", volumeID, err)
+			}
+		}
+	case blockMode:
+		klog.Infof("Creating VexFS volume %s as a raw block device", volumeID)
+		if err := createBlockVolume(volumePath, size); err != nil {
+			os.RemoveAll(volumePath)
+			return nil, status.Errorf(codes.Internal, "Failed to create block volume: %v", err)
+		}
+	default:
+		if err := os.MkdirAll(volumePath, 0755); err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to create volume directory: %v", err)
+		}
+	}
+
+	// Create VexFS metadata
+	accessType := "mount"
+	if blockMode {
+		accessType = "block"
+	}
+	metadataPath := filepath.Join(volumePath, "vexfs.meta")
+	metadata := fmt.Sprintf("volume_id=%s\nsize=%d\naccess_type=%s\ncreated_by=vexfs-csi\n", volumeID, size, accessType)
+	if err := os.WriteFile(metadataPath, []byte(metadata), 0644); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to create volume metadata: %v", err)
+	}
+
+	volume := &csi.Volume{
+		VolumeId:           volumeID,
+		CapacityBytes:      size,
+		VolumeContext:      parameters,
+		ContentSource:      req.GetVolumeContentSource(),
+		AccessibleTopology: selectTopology(req.GetAccessibilityRequirements()),
+	}
+
+	return &csi.CreateVolumeResponse{Volume: volume}, nil
+}
+
+// selectTopology picks the segment CreateVolume should pin the new volume
+// to, preferring the scheduler's ranked Preferred list (from a pod already
+// bound to a node) and falling back to the first Requisite entry.
+func selectTopology(req *csi.TopologyRequirement) []*csi.Topology {
+	if req == nil {
+		return nil
+	}
+	if preferred := req.GetPreferred(); len(preferred) > 0 {
+		return []*csi.Topology{preferred[0]}
+	}
+	if requisite := req.GetRequisite(); len(requisite) > 0 {
+		return []*csi.Topology{requisite[0]}
+	}
+	return nil
+}
+
+// DeleteVolume deletes a VexFS volume
+func (c *Controller) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequest) (*csi.DeleteVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+
+	klog.Infof("Deleting VexFS volume %s", volumeID)
+
+	volumePath := filepath.Join(volumesDir, volumeID)
+	if err := os.RemoveAll(volumePath); err != nil && !os.IsNotExist(err) {
+		return nil, status.Errorf(codes.Internal, "Failed to delete volume: %v", err)
+	}
+
+	return &csi.DeleteVolumeResponse{}, nil
+}
+
+// ControllerPublishVolume attaches a volume to a node
+func (c *Controller) ControllerPublishVolume(ctx context.Context, req *csi.ControllerPublishVolumeRequest) (*csi.ControllerPublishVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+	nodeID := req.GetNodeId()
+
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+
+	if len(nodeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Node ID missing in request")
+	}
+
+	klog.Infof("Publishing VexFS volume %s to node %s", volumeID, nodeID)
+
+	return &csi.ControllerPublishVolumeResponse{}, nil
+}
+
+// ControllerUnpublishVolume detaches a volume from a node
+func (c *Controller) ControllerUnpublishVolume(ctx context.Context, req *csi.ControllerUnpublishVolumeRequest) (*csi.ControllerUnpublishVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+	nodeID := req.GetNodeId()
+
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+
+	klog.Infof("Unpublishing VexFS volume %s from node %s", volumeID, nodeID)
+
+	return &csi.ControllerUnpublishVolumeResponse{}, nil
+}
+
+// ValidateVolumeCapabilities validates volume capabilities
+func (c *Controller) ValidateVolumeCapabilities(ctx context.Context, req *csi.ValidateVolumeCapabilitiesRequest) (*csi.ValidateVolumeCapabilitiesResponse, error) {
+	volumeID := req.GetVolumeId()
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+
+	volumeCaps := req.GetVolumeCapabilities()
+	if volumeCaps == nil {
+		return nil, status.Error(codes.InvalidArgument, "Volume capabilities missing in request")
+	}
+
+	var confirmed *csi.ValidateVolumeCapabilitiesResponse_Confirmed
+	if err := c.validateVolumeCapabilities(volumeCaps); err == nil {
+		confirmed = &csi.ValidateVolumeCapabilitiesResponse_Confirmed{VolumeCapabilities: volumeCaps}
+	}
+
+	return &csi.ValidateVolumeCapabilitiesResponse{
+		Confirmed: confirmed,
+	}, nil
+}
+
+// ListVolumes lists all volumes
+func (c *Controller) ListVolumes(ctx context.Context, req *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
+	klog.V(5).Infof("ListVolumes called")
+
+	entries, err := os.ReadDir(volumesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &csi.ListVolumesResponse{}, nil
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to list volumes: %v", err)
+	}
+
+	var volumes []*csi.ListVolumesResponse_Entry
+	for _, entry := range entries {
+		if entry.IsDir() {
+			volumes = append(volumes, &csi.ListVolumesResponse_Entry{
+				Volume: &csi.Volume{
+					VolumeId: entry.Name(),
+				},
+			})
+		}
+	}
+
+	return &csi.ListVolumesResponse{
+		Entries: volumes,
+	}, nil
+}
+
+// GetCapacity returns the capacity of the storage pool
+func (c *Controller) GetCapacity(ctx context.Context, req *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
+	klog.V(5).Infof("GetCapacity called")
+	return &csi.GetCapacityResponse{}, nil
+}
+
+// ControllerGetCapabilities returns the capabilities of the controller service
+func (c *Controller) ControllerGetCapabilities(ctx context.Context, req *csi.ControllerGetCapabilitiesRequest) (*csi.ControllerGetCapabilitiesResponse, error) {
+	klog.V(5).Infof("ControllerGetCapabilities called")
+	return &csi.ControllerGetCapabilitiesResponse{
+		Capabilities: c.cscap,
+	}, nil
+}
+
+// CreateSnapshot creates a point-in-time snapshot of a VexFS volume,
+// including its HNSW/vector index, under snapshotsDir.
+func (c *Controller) CreateSnapshot(ctx context.Context, req *csi.CreateSnapshotRequest) (*csi.CreateSnapshotResponse, error) {
+	snapshotID := req.GetName()
+	if len(snapshotID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Snapshot name missing in request")
+	}
+
+	sourceVolumeID := req.GetSourceVolumeId()
+	if len(sourceVolumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Source volume ID missing in request")
+	}
+
+	volumePath := filepath.Join(volumesDir, sourceVolumeID)
+	if _, err := os.Stat(volumePath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, status.Errorf(codes.NotFound, "Source volume %s does not exist", sourceVolumeID)
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to stat source volume %s: %v", sourceVolumeID, err)
+	}
+
+	// Idempotent retry: a snapshot by this name already exists.
+	if meta, err := loadSnapshotMetadata(snapshotID); err == nil {
+		if meta.SourceVolumeID != sourceVolumeID {
+			return nil, status.Errorf(codes.AlreadyExists, "Snapshot %s already exists for a different source volume", snapshotID)
+		}
+		return &csi.CreateSnapshotResponse{Snapshot: meta.toCSI()}, nil
+	}
+
+	klog.Infof("Creating VexFS snapshot %s of volume %s", snapshotID, sourceVolumeID)
+
+	snapPath := snapshotPath(snapshotID)
+	if err := os.MkdirAll(snapPath, 0755); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to create snapshot directory: %v", err)
+	}
+
+	size, err := copyVolumeTree(volumePath, snapPath)
+	if err != nil {
+		os.RemoveAll(snapPath)
+		return nil, status.Errorf(codes.Internal, "Failed to copy volume %s into snapshot: %v", sourceVolumeID, err)
+	}
+
+	meta := &snapshotMetadata{
+		SnapshotID:     snapshotID,
+		SourceVolumeID: sourceVolumeID,
+		SizeBytes:      size,
+		CreationTime:   time.Now(),
+		ReadyToUse:     true,
+	}
+	if err := saveSnapshotMetadata(meta); err != nil {
+		os.RemoveAll(snapPath)
+		return nil, status.Errorf(codes.Internal, "Failed to persist snapshot metadata: %v", err)
+	}
+
+	return &csi.CreateSnapshotResponse{Snapshot: meta.toCSI()}, nil
+}
+
+// DeleteSnapshot deletes a snapshot
+func (c *Controller) DeleteSnapshot(ctx context.Context, req *csi.DeleteSnapshotRequest) (*csi.DeleteSnapshotResponse, error) {
+	snapshotID := req.GetSnapshotId()
+	if len(snapshotID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Snapshot ID missing in request")
+	}
+
+	klog.Infof("Deleting VexFS snapshot %s", snapshotID)
+
+	if err := os.RemoveAll(snapshotPath(snapshotID)); err != nil && !os.IsNotExist(err) {
+		return nil, status.Errorf(codes.Internal, "Failed to delete snapshot: %v", err)
+	}
+
+	return &csi.DeleteSnapshotResponse{}, nil
+}
+
+// ListSnapshots lists snapshots, honoring the snapshot_id/source_volume_id
+// filters and starting_token/max_entries pagination.
+func (c *Controller) ListSnapshots(ctx context.Context, req *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
+	klog.V(5).Infof("ListSnapshots called")
+
+	if req.GetMaxEntries() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "max_entries must not be negative, got %d", req.GetMaxEntries())
+	}
+
+	if snapshotID := req.GetSnapshotId(); len(snapshotID) > 0 {
+		meta, err := loadSnapshotMetadata(snapshotID)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return &csi.ListSnapshotsResponse{}, nil
+			}
+			return nil, status.Errorf(codes.Internal, "Failed to read snapshot %s: %v", snapshotID, err)
+		}
+		if sourceVolumeID := req.GetSourceVolumeId(); len(sourceVolumeID) > 0 && meta.SourceVolumeID != sourceVolumeID {
+			return &csi.ListSnapshotsResponse{}, nil
+		}
+		return &csi.ListSnapshotsResponse{
+			Entries: []*csi.ListSnapshotsResponse_Entry{{Snapshot: meta.toCSI()}},
+		}, nil
+	}
+
+	dirEntries, err := os.ReadDir(snapshotsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &csi.ListSnapshotsResponse{}, nil
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to list snapshots: %v", err)
+	}
+
+	var metas []*snapshotMetadata
+	for _, e := range dirEntries {
+		if !e.IsDir() {
+			continue
+		}
+		meta, err := loadSnapshotMetadata(e.Name())
+		if err != nil {
+			continue
+		}
+		if sourceVolumeID := req.GetSourceVolumeId(); len(sourceVolumeID) > 0 && meta.SourceVolumeID != sourceVolumeID {
+			continue
+		}
+		metas = append(metas, meta)
+	}
+	sort.Slice(metas, func(i, j int) bool { return metas[i].SnapshotID < metas[j].SnapshotID })
+
+	startIdx := 0
+	if token := req.GetStartingToken(); len(token) > 0 {
+		parsed, err := strconv.Atoi(token)
+		if err != nil || parsed < 0 || parsed > len(metas) {
+			return nil, status.Errorf(codes.Aborted, "Invalid starting_token %q", token)
+		}
+		startIdx = parsed
+	}
+
+	endIdx := len(metas)
+	nextToken := ""
+	if maxEntries := int(req.GetMaxEntries()); maxEntries > 0 && startIdx+maxEntries < len(metas) {
+		endIdx = startIdx + maxEntries
+		nextToken = strconv.Itoa(endIdx)
+	}
+
+	entries := make([]*csi.ListSnapshotsResponse_Entry, 0, endIdx-startIdx)
+	for _, meta := range metas[startIdx:endIdx] {
+		entries = append(entries, &csi.ListSnapshotsResponse_Entry{Snapshot: meta.toCSI()})
+	}
+
+	return &csi.ListSnapshotsResponse{
+		Entries:   entries,
+		NextToken: nextToken,
+	}, nil
+}
+
+// ControllerExpandVolume grows a VexFS volume's persisted metadata and
+// backing store. For a raw block volume, that means truncating block.img
+// to the new size here; NodeExpandVolume re-probes the loop device so the
+// kernel picks up the change. For a mounted volume, the actual filesystem
+// resize happens node-side once kubelet has remounted it, via
+// NodeExpandVolume.
+func (c *Controller) ControllerExpandVolume(ctx context.Context, req *csi.ControllerExpandVolumeRequest) (*csi.ControllerExpandVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+
+	capacityRange := req.GetCapacityRange()
+	if capacityRange == nil {
+		return nil, status.Error(codes.InvalidArgument, "Capacity range missing in request")
+	}
+
+	newSize := capacityRange.GetRequiredBytes()
+	if limit := capacityRange.GetLimitBytes(); limit > 0 && newSize > limit {
+		return nil, status.Errorf(codes.OutOfRange, "Requested size %d exceeds limit %d", newSize, limit)
+	}
+
+	volumePath := filepath.Join(volumesDir, volumeID)
+	metadataPath := filepath.Join(volumePath, "vexfs.meta")
+	fields, err := readVolumeMetadataFields(metadataPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, status.Errorf(codes.NotFound, "Volume %s does not exist", volumeID)
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to read volume metadata: %v", err)
+	}
+
+	currentSize, err := strconv.ParseInt(fields["size"], 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Invalid size= entry in %s: %v", metadataPath, err)
+	}
+
+	if newSize < currentSize {
+		return nil, status.Errorf(codes.OutOfRange, "Cannot shrink volume %s from %d to %d bytes", volumeID, currentSize, newSize)
+	}
+
+	klog.Infof("Expanding VexFS volume %s from %d to %d bytes", volumeID, currentSize, newSize)
+
+	if fields["access_type"] == "block" {
+		if err := os.Truncate(filepath.Join(volumePath, blockImageName), newSize); err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to resize block image for volume %s: %v", volumeID, err)
+		}
+	}
+
+	metadata := fmt.Sprintf("volume_id=%s\nsize=%d\naccess_type=%s\ncreated_by=vexfs-csi\n", volumeID, newSize, fields["access_type"])
+	if err := os.WriteFile(metadataPath, []byte(metadata), 0644); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to update volume metadata: %v", err)
+	}
+
+	return &csi.ControllerExpandVolumeResponse{
+		CapacityBytes:         newSize,
+		NodeExpansionRequired: true,
+	}, nil
+}
+
+// readVolumeMetadataFields parses a vexfs.meta key=value file into a map.
+func readVolumeMetadataFields(metadataPath string) (map[string]string, error) {
+	data, err := os.ReadFile(metadataPath)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if key, value, ok := strings.Cut(line, "="); ok {
+			fields[key] = value
+		}
+	}
+	return fields, nil
+}
+
+// blockImageName is shared with the node package via volumestore.BlockImageName.
+const blockImageName = volumestore.BlockImageName
+
+// hasBlockAccessType reports whether any of the requested capabilities ask
+// for the raw Block access type rather than a mounted filesystem.
+func hasBlockAccessType(caps []*csi.VolumeCapability) bool {
+	for _, c := range caps {
+		if c.GetBlock() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// createBlockVolume backs a volume with a sparse file sized for losetup to
+// expose as a block device, rather than a plain directory.
+func createBlockVolume(volumePath string, size int64) error {
+	if err := os.MkdirAll(volumePath, 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(filepath.Join(volumePath, blockImageName))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return f.Truncate(size)
+}
+
+// ControllerGetVolume gets volume information
+func (c *Controller) ControllerGetVolume(ctx context.Context, req *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "ControllerGetVolume not implemented")
+}
@@ -0,0 +1,324 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// withTempDirs points volumesDir/snapshotsDir at a scratch directory for
+// the duration of the test, so tests don't touch the real
+// /var/lib/vexfs on the machine running them.
+func withTempDirs(t *testing.T) {
+	t.Helper()
+	origVolumes, origSnapshots := volumesDir, snapshotsDir
+	volumesDir = filepath.Join(t.TempDir(), "volumes")
+	snapshotsDir = filepath.Join(t.TempDir(), "snapshots")
+	t.Cleanup(func() {
+		volumesDir, snapshotsDir = origVolumes, origSnapshots
+	})
+}
+
+func mountCapability() *csi.VolumeCapability {
+	return &csi.VolumeCapability{
+		AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+		AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+	}
+}
+
+func blockCapability() *csi.VolumeCapability {
+	return &csi.VolumeCapability{
+		AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}},
+		AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+	}
+}
+
+func TestCreateVolumeMountMode(t *testing.T) {
+	withTempDirs(t)
+	c := New(0)
+
+	resp, err := c.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+		Name:               "vol-1",
+		VolumeCapabilities: []*csi.VolumeCapability{mountCapability()},
+		CapacityRange:      &csi.CapacityRange{RequiredBytes: 1024},
+	})
+	if err != nil {
+		t.Fatalf("CreateVolume: %v", err)
+	}
+	if resp.Volume.CapacityBytes != 1024 {
+		t.Fatalf("CapacityBytes = %d, want 1024", resp.Volume.CapacityBytes)
+	}
+
+	data, err := os.ReadFile(filepath.Join(volumesDir, "vol-1", "vexfs.meta"))
+	if err != nil {
+		t.Fatalf("ReadFile vexfs.meta: %v", err)
+	}
+	if !strings.Contains(string(data), "access_type=mount") {
+		t.Fatalf("vexfs.meta = %q, want access_type=mount", data)
+	}
+}
+
+func TestCreateVolumeBlockMode(t *testing.T) {
+	withTempDirs(t)
+	c := New(0)
+
+	resp, err := c.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+		Name:               "vol-1",
+		VolumeCapabilities: []*csi.VolumeCapability{blockCapability()},
+		CapacityRange:      &csi.CapacityRange{RequiredBytes: 4096},
+	})
+	if err != nil {
+		t.Fatalf("CreateVolume: %v", err)
+	}
+	if resp.Volume.CapacityBytes != 4096 {
+		t.Fatalf("CapacityBytes = %d, want 4096", resp.Volume.CapacityBytes)
+	}
+
+	fi, err := os.Stat(filepath.Join(volumesDir, "vol-1", blockImageName))
+	if err != nil {
+		t.Fatalf("Stat block image: %v", err)
+	}
+	if fi.Size() != 4096 {
+		t.Fatalf("block image size = %d, want 4096", fi.Size())
+	}
+}
+
+func TestCreateVolumeFromSnapshotBlockModeResizesToRequestedCapacity(t *testing.T) {
+	withTempDirs(t)
+	c := New(0)
+
+	if _, err := c.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+		Name:               "source",
+		VolumeCapabilities: []*csi.VolumeCapability{blockCapability()},
+		CapacityRange:      &csi.CapacityRange{RequiredBytes: 1024},
+	}); err != nil {
+		t.Fatalf("CreateVolume(source): %v", err)
+	}
+
+	if _, err := c.CreateSnapshot(context.Background(), &csi.CreateSnapshotRequest{
+		Name:           "snap-1",
+		SourceVolumeId: "source",
+	}); err != nil {
+		t.Fatalf("CreateSnapshot: %v", err)
+	}
+
+	resp, err := c.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+		Name:               "restored",
+		VolumeCapabilities: []*csi.VolumeCapability{blockCapability()},
+		CapacityRange:      &csi.CapacityRange{RequiredBytes: 8192},
+		VolumeContentSource: &csi.VolumeContentSource{
+			Type: &csi.VolumeContentSource_Snapshot{
+				Snapshot: &csi.VolumeContentSource_SnapshotSource{SnapshotId: "snap-1"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateVolume(restored): %v", err)
+	}
+	if resp.Volume.CapacityBytes != 8192 {
+		t.Fatalf("CapacityBytes = %d, want 8192", resp.Volume.CapacityBytes)
+	}
+
+	fi, err := os.Stat(filepath.Join(volumesDir, "restored", blockImageName))
+	if err != nil {
+		t.Fatalf("Stat restored block image: %v", err)
+	}
+	if fi.Size() != 8192 {
+		t.Fatalf("restored block image size = %d, want 8192 (block.img was left at the snapshot's original size)", fi.Size())
+	}
+}
+
+func TestControllerExpandVolumeGrowsMetadata(t *testing.T) {
+	withTempDirs(t)
+	c := New(0)
+
+	if _, err := c.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+		Name:               "vol-1",
+		VolumeCapabilities: []*csi.VolumeCapability{mountCapability()},
+		CapacityRange:      &csi.CapacityRange{RequiredBytes: 1024},
+	}); err != nil {
+		t.Fatalf("CreateVolume: %v", err)
+	}
+
+	resp, err := c.ControllerExpandVolume(context.Background(), &csi.ControllerExpandVolumeRequest{
+		VolumeId:      "vol-1",
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 2048},
+	})
+	if err != nil {
+		t.Fatalf("ControllerExpandVolume: %v", err)
+	}
+	if resp.CapacityBytes != 2048 {
+		t.Fatalf("CapacityBytes = %d, want 2048", resp.CapacityBytes)
+	}
+
+	data, err := os.ReadFile(filepath.Join(volumesDir, "vol-1", "vexfs.meta"))
+	if err != nil {
+		t.Fatalf("ReadFile vexfs.meta: %v", err)
+	}
+	if !strings.Contains(string(data), "size=2048") {
+		t.Fatalf("vexfs.meta = %q, want size=2048", data)
+	}
+	if !strings.Contains(string(data), "access_type=mount") {
+		t.Fatalf("vexfs.meta = %q, want access_type=mount to survive the expand", data)
+	}
+}
+
+func TestControllerExpandVolumeBlockModeResizesBlockImage(t *testing.T) {
+	withTempDirs(t)
+	c := New(0)
+
+	if _, err := c.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+		Name:               "vol-1",
+		VolumeCapabilities: []*csi.VolumeCapability{blockCapability()},
+		CapacityRange:      &csi.CapacityRange{RequiredBytes: 1024},
+	}); err != nil {
+		t.Fatalf("CreateVolume: %v", err)
+	}
+
+	if _, err := c.ControllerExpandVolume(context.Background(), &csi.ControllerExpandVolumeRequest{
+		VolumeId:      "vol-1",
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 4096},
+	}); err != nil {
+		t.Fatalf("ControllerExpandVolume: %v", err)
+	}
+
+	fi, err := os.Stat(filepath.Join(volumesDir, "vol-1", blockImageName))
+	if err != nil {
+		t.Fatalf("Stat block image: %v", err)
+	}
+	if fi.Size() != 4096 {
+		t.Fatalf("block image size = %d, want 4096", fi.Size())
+	}
+}
+
+func TestControllerExpandVolumeRejectsShrink(t *testing.T) {
+	withTempDirs(t)
+	c := New(0)
+
+	if _, err := c.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+		Name:               "vol-1",
+		VolumeCapabilities: []*csi.VolumeCapability{mountCapability()},
+		CapacityRange:      &csi.CapacityRange{RequiredBytes: 4096},
+	}); err != nil {
+		t.Fatalf("CreateVolume: %v", err)
+	}
+
+	_, err := c.ControllerExpandVolume(context.Background(), &csi.ControllerExpandVolumeRequest{
+		VolumeId:      "vol-1",
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 1024},
+	})
+	if err == nil {
+		t.Fatal("ControllerExpandVolume shrinking a volume returned nil error, want OutOfRange")
+	}
+	if code := status.Code(err); code != codes.OutOfRange {
+		t.Fatalf("ControllerExpandVolume shrinking a volume returned code %v, want %v", code, codes.OutOfRange)
+	}
+}
+
+func TestCreateSnapshotIdempotentRetry(t *testing.T) {
+	withTempDirs(t)
+	c := New(0)
+
+	if _, err := c.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+		Name:               "vol-1",
+		VolumeCapabilities: []*csi.VolumeCapability{mountCapability()},
+	}); err != nil {
+		t.Fatalf("CreateVolume: %v", err)
+	}
+
+	req := &csi.CreateSnapshotRequest{Name: "snap-1", SourceVolumeId: "vol-1"}
+	first, err := c.CreateSnapshot(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateSnapshot (first): %v", err)
+	}
+	second, err := c.CreateSnapshot(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateSnapshot (retry): %v", err)
+	}
+	if first.Snapshot.SnapshotId != second.Snapshot.SnapshotId || !first.Snapshot.CreationTime.AsTime().Equal(second.Snapshot.CreationTime.AsTime()) {
+		t.Fatalf("retried CreateSnapshot returned a different snapshot: %+v vs %+v", first.Snapshot, second.Snapshot)
+	}
+}
+
+func TestCreateSnapshotConflictingSourceVolume(t *testing.T) {
+	withTempDirs(t)
+	c := New(0)
+
+	for _, name := range []string{"vol-1", "vol-2"} {
+		if _, err := c.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+			Name:               name,
+			VolumeCapabilities: []*csi.VolumeCapability{mountCapability()},
+		}); err != nil {
+			t.Fatalf("CreateVolume(%s): %v", name, err)
+		}
+	}
+
+	if _, err := c.CreateSnapshot(context.Background(), &csi.CreateSnapshotRequest{Name: "snap-1", SourceVolumeId: "vol-1"}); err != nil {
+		t.Fatalf("CreateSnapshot: %v", err)
+	}
+
+	_, err := c.CreateSnapshot(context.Background(), &csi.CreateSnapshotRequest{Name: "snap-1", SourceVolumeId: "vol-2"})
+	if err == nil {
+		t.Fatal("CreateSnapshot with a conflicting source volume returned nil error, want AlreadyExists")
+	}
+	if code := status.Code(err); code != codes.AlreadyExists {
+		t.Fatalf("CreateSnapshot with a conflicting source volume returned code %v, want %v", code, codes.AlreadyExists)
+	}
+}
+
+func TestListSnapshotsPagination(t *testing.T) {
+	withTempDirs(t)
+	c := New(0)
+
+	for _, name := range []string{"vol-a", "vol-b", "vol-c"} {
+		if _, err := c.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+			Name:               name,
+			VolumeCapabilities: []*csi.VolumeCapability{mountCapability()},
+		}); err != nil {
+			t.Fatalf("CreateVolume(%s): %v", name, err)
+		}
+		if _, err := c.CreateSnapshot(context.Background(), &csi.CreateSnapshotRequest{Name: "snap-" + name, SourceVolumeId: name}); err != nil {
+			t.Fatalf("CreateSnapshot(%s): %v", name, err)
+		}
+	}
+
+	first, err := c.ListSnapshots(context.Background(), &csi.ListSnapshotsRequest{MaxEntries: 2})
+	if err != nil {
+		t.Fatalf("ListSnapshots (first page): %v", err)
+	}
+	if len(first.Entries) != 2 {
+		t.Fatalf("first page has %d entries, want 2", len(first.Entries))
+	}
+	if first.NextToken == "" {
+		t.Fatal("first page has no NextToken, want one since a third snapshot remains")
+	}
+
+	second, err := c.ListSnapshots(context.Background(), &csi.ListSnapshotsRequest{MaxEntries: 2, StartingToken: first.NextToken})
+	if err != nil {
+		t.Fatalf("ListSnapshots (second page): %v", err)
+	}
+	if len(second.Entries) != 1 {
+		t.Fatalf("second page has %d entries, want 1", len(second.Entries))
+	}
+	if second.NextToken != "" {
+		t.Fatalf("second page has NextToken %q, want empty", second.NextToken)
+	}
+}
+
+func TestListSnapshotsRejectsNegativeMaxEntries(t *testing.T) {
+	c := New(0)
+
+	_, err := c.ListSnapshots(context.Background(), &csi.ListSnapshotsRequest{MaxEntries: -1})
+	if err == nil {
+		t.Fatal("ListSnapshots with a negative max_entries returned nil error, want InvalidArgument")
+	}
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Fatalf("ListSnapshots with a negative max_entries returned code %v, want %v", code, codes.InvalidArgument)
+	}
+}
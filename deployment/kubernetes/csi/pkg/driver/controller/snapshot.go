@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/vexfs/vexfs/deployment/kubernetes/csi/pkg/driver/volumestore"
+)
+
+// snapshotsDir is where each VexFS snapshot's metadata and copied payload
+// live. volumesDir (the source side of a snapshot) is shared with the node
+// package via volumestore.Dir. Both are vars, not consts, so tests can
+// point them at a temporary directory instead of the real /var/lib/vexfs.
+var (
+	volumesDir   = volumestore.Dir
+	snapshotsDir = volumestore.SnapshotsDir
+)
+
+// snapshotMetadata is the on-disk record for a VexFS snapshot, persisted as
+// <snapshotsDir>/<snapshot-id>/snapshot.meta.
+type snapshotMetadata struct {
+	SnapshotID     string    `json:"snapshot_id"`
+	SourceVolumeID string    `json:"source_volume_id"`
+	SizeBytes      int64     `json:"size_bytes"`
+	CreationTime   time.Time `json:"creation_time"`
+	ReadyToUse     bool      `json:"ready_to_use"`
+}
+
+func snapshotPath(snapshotID string) string {
+	return filepath.Join(snapshotsDir, snapshotID)
+}
+
+func snapshotMetaPath(snapshotID string) string {
+	return filepath.Join(snapshotPath(snapshotID), "snapshot.meta")
+}
+
+func loadSnapshotMetadata(snapshotID string) (*snapshotMetadata, error) {
+	data, err := os.ReadFile(snapshotMetaPath(snapshotID))
+	if err != nil {
+		return nil, err
+	}
+	meta := &snapshotMetadata{}
+	if err := json.Unmarshal(data, meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+func saveSnapshotMetadata(meta *snapshotMetadata) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(snapshotMetaPath(meta.SnapshotID), data, 0644)
+}
+
+func (m *snapshotMetadata) toCSI() *csi.Snapshot {
+	return &csi.Snapshot{
+		SnapshotId:     m.SnapshotID,
+		SourceVolumeId: m.SourceVolumeID,
+		SizeBytes:      m.SizeBytes,
+		CreationTime:   timestamppb.New(m.CreationTime),
+		ReadyToUse:     m.ReadyToUse,
+	}
+}
+
+// copyVolumeTree copies a volume's payload and its HNSW/vector index files
+// from src into dst. Regular files are copied in full rather than
+// hard-linked, so later in-place writes to either tree (including the
+// vexfs.meta rewrite on restore and a block.img truncate on expand) don't
+// show through in the other. Returns the total size in bytes of the files
+// copied.
+func copyVolumeTree(src, dst string) (int64, error) {
+	var total int64
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode())
+		}
+
+		if copyErr := copyFile(path, target, info.Mode()); copyErr != nil {
+			return copyErr
+		}
+		total += info.Size()
+		return nil
+	})
+	return total, err
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
@@ -1,6 +1,10 @@
+// Package driver wires the CSI Identity/Controller/Node services together
+// into a single gRPC server, registering only the services called for by
+// the configured Mode.
 package driver
 
 import (
+	"fmt"
 	"net"
 	"net/url"
 	"os"
@@ -10,6 +14,10 @@ import (
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"google.golang.org/grpc"
 	"k8s.io/klog/v2"
+
+	"github.com/vexfs/vexfs/deployment/kubernetes/csi/pkg/driver/controller"
+	"github.com/vexfs/vexfs/deployment/kubernetes/csi/pkg/driver/identity"
+	"github.com/vexfs/vexfs/deployment/kubernetes/csi/pkg/driver/node"
 )
 
 const (
@@ -17,62 +25,71 @@ const (
 	DefaultVersion    = "1.0.0"
 )
 
+// Mode selects which CSI gRPC services a Driver registers. ModeAll runs
+// Controller and Node in the same process for backward compatibility with
+// single-binary deployments.
+type Mode string
+
+const (
+	ModeAll        Mode = "all"
+	ModeController Mode = "controller"
+	ModeNode       Mode = "node"
+)
+
 // Driver implements the CSI specification
 type Driver struct {
-	name       string
-	version    string
-	nodeID     string
-	maxVolumes int64
-
-	srv   *grpc.Server
-	cap   []*csi.VolumeCapability_AccessMode
-	cscap []*csi.ControllerServiceCapability
-	nscap []*csi.NodeServiceCapability
+	mode       Mode
+	driverName string
+
+	identity   *identity.Identity
+	controller *controller.Controller
+	node       *node.Node
+
+	volumeLocks *volumeLocks
+	srv         *grpc.Server
 }
 
-// NewDriver creates a new VexFS CSI driver
-func NewDriver(driverName, version, nodeID string, maxVolumes int64) (*Driver, error) {
+// NewDriver creates a new VexFS CSI driver that registers the Identity
+// service plus whichever of Controller/Node the given mode calls for.
+// ephemeral enables CSI ephemeral inline volume support on the Node service.
+// topologyKeys selects which topology segments the Node service reports via
+// NodeGetInfo; see node.ValidTopologyKeys.
+func NewDriver(mode Mode, driverName, version, nodeID string, maxVolumes int64, ephemeral bool, topologyKeys []string) (*Driver, error) {
 	if driverName == "" {
 		driverName = DefaultDriverName
 	}
 	if version == "" {
 		version = DefaultVersion
 	}
+	if mode == "" {
+		mode = ModeAll
+	}
+
+	klog.Infof("Driver: %v version: %v mode: %v", driverName, version, mode)
 
-	klog.Infof("Driver: %v version: %v", driverName, version)
+	d := &Driver{mode: mode, driverName: driverName, volumeLocks: newVolumeLocks()}
 
-	d := &Driver{
-		name:       driverName,
-		version:    version,
-		nodeID:     nodeID,
-		maxVolumes: maxVolumes,
+	switch mode {
+	case ModeController:
+		d.controller = controller.New(maxVolumes)
+	case ModeNode:
+		d.node = node.New(nodeID, maxVolumes, ephemeral, topologyKeys)
+	case ModeAll:
+		d.controller = controller.New(maxVolumes)
+		d.node = node.New(nodeID, maxVolumes, ephemeral, topologyKeys)
+	default:
+		return nil, fmt.Errorf("unknown driver mode %q", mode)
 	}
 
-	d.AddVolumeCapabilityAccessModes([]csi.VolumeCapability_AccessMode_Mode{
-		csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
-		csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY,
-		csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
-	})
-
-	d.AddControllerServiceCapabilities([]csi.ControllerServiceCapability_RPC_Type{
-		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
-		csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
-		csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
-		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
-		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
-	})
-
-	d.AddNodeServiceCapabilities([]csi.NodeServiceCapability_RPC_Type{
-		csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
-		csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
-		csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
-	})
+	d.identity = identity.New(driverName, version, d.controller != nil)
 
 	return d, nil
 }
 
-// Run starts the CSI driver
-func (d *Driver) Run(endpoint string) error {
+// Run starts the CSI driver, serving gRPC on endpoint. If httpEndpoint is
+// non-empty, it also starts a plain HTTP server on it exposing /metrics and
+// /healthz.
+func (d *Driver) Run(endpoint, httpEndpoint string) error {
 	u, err := url.Parse(endpoint)
 	if err != nil {
 		return err
@@ -93,10 +110,22 @@ func (d *Driver) Run(endpoint string) error {
 		return err
 	}
 
-	d.srv = grpc.NewServer()
-	csi.RegisterIdentityServer(d.srv, d)
-	csi.RegisterControllerServer(d.srv, d)
-	csi.RegisterNodeServer(d.srv, d)
+	if httpEndpoint != "" {
+		go d.serveHTTP(httpEndpoint)
+	}
+
+	d.srv = grpc.NewServer(grpc.ChainUnaryInterceptor(
+		loggingInterceptor(d.driverName),
+		metricsInterceptor(d.driverName),
+		idempotencyInterceptor(d.volumeLocks),
+	))
+	csi.RegisterIdentityServer(d.srv, d.identity)
+	if d.controller != nil {
+		csi.RegisterControllerServer(d.srv, d.controller)
+	}
+	if d.node != nil {
+		csi.RegisterNodeServer(d.srv, d.node)
+	}
 
 	klog.Infof("Listening for connections on address: %#v", listener.Addr())
 	return d.srv.Serve(listener)
@@ -107,65 +136,3 @@ func (d *Driver) Stop() {
 	klog.Infof("Stopping server")
 	d.srv.Stop()
 }
-
-// AddVolumeCapabilityAccessModes adds volume capability access modes
-func (d *Driver) AddVolumeCapabilityAccessModes(vc []csi.VolumeCapability_AccessMode_Mode) {
-	var vca []*csi.VolumeCapability_AccessMode
-	for _, c := range vc {
-		klog.Infof("Enabling volume access mode: %v", c.String())
-		vca = append(vca, &csi.VolumeCapability_AccessMode{Mode: c})
-	}
-	d.cap = vca
-}
-
-// AddControllerServiceCapabilities adds controller service capabilities
-func (d *Driver) AddControllerServiceCapabilities(cl []csi.ControllerServiceCapability_RPC_Type) {
-	var csc []*csi.ControllerServiceCapability
-	for _, c := range cl {
-		klog.Infof("Enabling controller service capability: %v", c.String())
-		csc = append(csc, &csi.ControllerServiceCapability{
-			Type: &csi.ControllerServiceCapability_Rpc{
-				Rpc: &csi.ControllerServiceCapability_RPC{
-					Type: c,
-				},
-			},
-		})
-	}
-	d.cscap = csc
-}
-
-// AddNodeServiceCapabilities adds node service capabilities
-func (d *Driver) AddNodeServiceCapabilities(nl []csi.NodeServiceCapability_RPC_Type) {
-	var nsc []*csi.NodeServiceCapability
-	for _, n := range nl {
-		klog.Infof("Enabling node service capability: %v", n.String())
-		nsc = append(nsc, &csi.NodeServiceCapability{
-			Type: &csi.NodeServiceCapability_Rpc{
-				Rpc: &csi.NodeServiceCapability_RPC{
-					Type: n,
-				},
-			},
-		})
-	}
-	d.nscap = nsc
-}
-
-// ValidateVolumeCapabilities validates volume capabilities
-func (d *Driver) ValidateVolumeCapabilities(volumeCaps []*csi.VolumeCapability) error {
-	for _, volCap := range volumeCaps {
-		if volCap.GetAccessMode() == nil {
-			return nil
-		}
-		supported := false
-		for _, c := range d.cap {
-			if c.GetMode() == volCap.GetAccessMode().GetMode() {
-				supported = true
-				break
-			}
-		}
-		if !supported {
-			return nil
-		}
-	}
-	return nil
-}
@@ -0,0 +1,34 @@
+package driver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2"
+)
+
+// serveHTTP starts a plain HTTP server exposing /metrics (Prometheus) and
+// /healthz (the same check the CSI Probe RPC performs). It runs until the
+// driver process exits; a listen failure is logged rather than fatal, since
+// losing metrics/health shouldn't take down an otherwise-working CSI driver.
+func (d *Driver) serveHTTP(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", d.healthzHandler)
+
+	klog.Infof("Serving metrics and health checks on %s", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		klog.Errorf("HTTP server on %s exited: %v", addr, err)
+	}
+}
+
+func (d *Driver) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	if _, err := d.identity.Probe(context.Background(), &csi.ProbeRequest{}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
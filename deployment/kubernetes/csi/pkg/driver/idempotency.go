@@ -0,0 +1,53 @@
+package driver
+
+import (
+	"sync"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+)
+
+// volumeLocks serializes concurrent CSI RPCs that target the same volume or
+// snapshot name, so a retried CreateVolume/DeleteVolume call racing an
+// in-flight one for the same name doesn't corrupt /var/lib/vexfs/volumes/<id>.
+type volumeLocks struct {
+	mu    sync.Mutex
+	inUse map[string]*sync.Mutex
+}
+
+func newVolumeLocks() *volumeLocks {
+	return &volumeLocks{inUse: make(map[string]*sync.Mutex)}
+}
+
+// lock acquires the mutex for key, creating it on first use, and returns a
+// function that releases it.
+func (l *volumeLocks) lock(key string) func() {
+	l.mu.Lock()
+	m, ok := l.inUse[key]
+	if !ok {
+		m = &sync.Mutex{}
+		l.inUse[key] = m
+	}
+	l.mu.Unlock()
+
+	m.Lock()
+	return m.Unlock
+}
+
+// idempotencyKey extracts the volume or snapshot name a request targets, so
+// idempotencyInterceptor knows what to serialize concurrent calls on.
+func idempotencyKey(req interface{}) (string, bool) {
+	switch r := req.(type) {
+	case *csi.CreateVolumeRequest:
+		return "volume:" + r.GetName(), true
+	case *csi.DeleteVolumeRequest:
+		return "volume:" + r.GetVolumeId(), true
+	case *csi.ControllerExpandVolumeRequest:
+		return "volume:" + r.GetVolumeId(), true
+	case *csi.CreateSnapshotRequest:
+		return "snapshot:" + r.GetName(), true
+	case *csi.DeleteSnapshotRequest:
+		return "snapshot:" + r.GetSnapshotId(), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,63 @@
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     interface{}
+		wantKey string
+		wantOK  bool
+	}{
+		{name: "create volume", req: &csi.CreateVolumeRequest{Name: "vol-1"}, wantKey: "volume:vol-1", wantOK: true},
+		{name: "delete volume", req: &csi.DeleteVolumeRequest{VolumeId: "vol-1"}, wantKey: "volume:vol-1", wantOK: true},
+		{name: "expand volume", req: &csi.ControllerExpandVolumeRequest{VolumeId: "vol-1"}, wantKey: "volume:vol-1", wantOK: true},
+		{name: "create snapshot", req: &csi.CreateSnapshotRequest{Name: "snap-1"}, wantKey: "snapshot:snap-1", wantOK: true},
+		{name: "delete snapshot", req: &csi.DeleteSnapshotRequest{SnapshotId: "snap-1"}, wantKey: "snapshot:snap-1", wantOK: true},
+		{name: "unrelated request", req: &csi.ListVolumesRequest{}, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, ok := idempotencyKey(tt.req)
+			if ok != tt.wantOK {
+				t.Fatalf("idempotencyKey(%T) ok = %v, want %v", tt.req, ok, tt.wantOK)
+			}
+			if ok && key != tt.wantKey {
+				t.Fatalf("idempotencyKey(%T) = %q, want %q", tt.req, key, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestVolumeLocksSerializesSameKey(t *testing.T) {
+	locks := newVolumeLocks()
+
+	unlockA := locks.lock("volume:vol-1")
+
+	acquired := make(chan struct{})
+	go func() {
+		unlockB := locks.lock("volume:vol-1")
+		close(acquired)
+		unlockB()
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second lock on the same key was acquired while the first was still held")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlockA()
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("second lock was never acquired after the first was released")
+	}
+}
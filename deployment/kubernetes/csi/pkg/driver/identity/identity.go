@@ -0,0 +1,79 @@
+// Package identity implements the CSI Identity service shared by every
+// VexFS CSI binary, regardless of which other services (Controller, Node)
+// it runs.
+package identity
+
+import (
+	"context"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// Identity implements csi.IdentityServer.
+type Identity struct {
+	name          string
+	version       string
+	hasController bool
+}
+
+// New creates an Identity service. hasController controls whether
+// GetPluginCapabilities advertises CONTROLLER_SERVICE and topology support,
+// so a node-only binary doesn't claim capabilities it can't serve.
+func New(name, version string, hasController bool) *Identity {
+	return &Identity{name: name, version: version, hasController: hasController}
+}
+
+// GetPluginInfo returns plugin information
+func (i *Identity) GetPluginInfo(ctx context.Context, req *csi.GetPluginInfoRequest) (*csi.GetPluginInfoResponse, error) {
+	klog.V(5).Infof("Using default GetPluginInfo")
+
+	if i.name == "" {
+		return nil, status.Error(codes.Unavailable, "Driver name not configured")
+	}
+
+	if i.version == "" {
+		return nil, status.Error(codes.Unavailable, "Driver is missing version")
+	}
+
+	return &csi.GetPluginInfoResponse{
+		Name:          i.name,
+		VendorVersion: i.version,
+	}, nil
+}
+
+// Probe returns the health and readiness of the plugin
+func (i *Identity) Probe(ctx context.Context, req *csi.ProbeRequest) (*csi.ProbeResponse, error) {
+	klog.V(5).Infof("Using default Probe")
+	return &csi.ProbeResponse{}, nil
+}
+
+// GetPluginCapabilities returns the capabilities of the plugin
+func (i *Identity) GetPluginCapabilities(ctx context.Context, req *csi.GetPluginCapabilitiesRequest) (*csi.GetPluginCapabilitiesResponse, error) {
+	klog.V(5).Infof("Using default GetPluginCapabilities")
+
+	if !i.hasController {
+		return &csi.GetPluginCapabilitiesResponse{}, nil
+	}
+
+	return &csi.GetPluginCapabilitiesResponse{
+		Capabilities: []*csi.PluginCapability{
+			{
+				Type: &csi.PluginCapability_Service_{
+					Service: &csi.PluginCapability_Service{
+						Type: csi.PluginCapability_Service_CONTROLLER_SERVICE,
+					},
+				},
+			},
+			{
+				Type: &csi.PluginCapability_Service_{
+					Service: &csi.PluginCapability_Service{
+						Type: csi.PluginCapability_Service_VOLUME_ACCESSIBILITY_CONSTRAINTS,
+					},
+				},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,64 @@
+package driver
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// loggingInterceptor logs each RPC's method, latency, and outcome.
+func loggingInterceptor(driverName string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		klog.V(4).Infof("%s: %s called with request: %+v", driverName, info.FullMethod, req)
+
+		resp, err := handler(ctx, req)
+
+		if err != nil {
+			klog.Errorf("%s: %s failed after %s: %v", driverName, info.FullMethod, time.Since(start), err)
+		} else {
+			klog.V(4).Infof("%s: %s succeeded after %s", driverName, info.FullMethod, time.Since(start))
+		}
+		return resp, err
+	}
+}
+
+// metricsInterceptor records csi_operations_seconds for every RPC.
+func metricsInterceptor(driverName string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		operationDuration.WithLabelValues(driverName, rpcName(info.FullMethod), status.Code(err).String()).
+			Observe(time.Since(start).Seconds())
+
+		return resp, err
+	}
+}
+
+// idempotencyInterceptor serializes concurrent RPCs that target the same
+// volume or snapshot name, per volumeLocks.
+func idempotencyInterceptor(locks *volumeLocks) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		key, ok := idempotencyKey(req)
+		if !ok {
+			return handler(ctx, req)
+		}
+		unlock := locks.lock(key)
+		defer unlock()
+		return handler(ctx, req)
+	}
+}
+
+// rpcName trims a gRPC FullMethod ("/csi.v1.Controller/CreateVolume") down
+// to just the RPC name, for use as a low-cardinality metric label.
+func rpcName(fullMethod string) string {
+	if idx := strings.LastIndex(fullMethod, "/"); idx >= 0 {
+		return fullMethod[idx+1:]
+	}
+	return fullMethod
+}
@@ -0,0 +1,52 @@
+package driver
+
+import (
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/vexfs/vexfs/deployment/kubernetes/csi/pkg/driver/volumestore"
+)
+
+var (
+	// operationDuration records how long each CSI RPC took, labeled by the
+	// resulting gRPC status so failed and slow calls are distinguishable.
+	operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "csi_operations_seconds",
+		Help: "Duration of CSI RPCs, labeled by driver, method, and grpc_status.",
+	}, []string{"driver", "method", "grpc_status"})
+
+	// volumesTotal and snapshotsTotal are computed at scrape time by
+	// counting directories under volumestore.Dir/SnapshotsDir, the same way
+	// ListVolumes/ListSnapshots do. Maintaining them via Inc/Dec on
+	// Create/Delete instead would reset to 0 on every driver restart and
+	// drift further negative on every retried idempotent delete, since
+	// os.RemoveAll of an already-gone path returns nil.
+	volumesTotal = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "csi_volumes_total",
+		Help: "Number of VexFS volumes currently provisioned.",
+	}, func() float64 { return float64(countDirs(volumestore.Dir)) })
+
+	snapshotsTotal = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "csi_snapshots_total",
+		Help: "Number of VexFS snapshots currently provisioned.",
+	}, func() float64 { return float64(countDirs(volumestore.SnapshotsDir)) })
+)
+
+// countDirs counts the subdirectories of dir, the on-disk unit for both a
+// VexFS volume and a VexFS snapshot. A missing dir counts as zero rather
+// than an error, since nothing has been provisioned yet.
+func countDirs(dir string) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, e := range entries {
+		if e.IsDir() {
+			n++
+		}
+	}
+	return n
+}
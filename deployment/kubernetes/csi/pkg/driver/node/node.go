@@ -0,0 +1,473 @@
+// Package node implements the CSI Node service for VexFS.
+package node
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+
+	"github.com/vexfs/vexfs/deployment/kubernetes/csi/pkg/driver/volumestore"
+)
+
+// volumesDir is shared with the controller package via volumestore, which
+// is the source of truth for a volume's on-disk layout. It's a var, not a
+// const, so tests can point it at a temporary directory.
+var volumesDir = volumestore.Dir
+
+const (
+	// blockImageName is shared with the controller package via volumestore.
+	blockImageName = volumestore.BlockImageName
+
+	// ephemeralContextKey marks a NodePublishVolume request as a CSI
+	// ephemeral inline volume (https://kubernetes-csi.github.io/docs/ephemeral-local-volumes.html).
+	ephemeralContextKey = "csi.storage.k8s.io/ephemeral"
+
+	// VexFS inline volume parameters, supplied via volume_context on
+	// ephemeral volumes embedded directly in a pod spec.
+	vexfsVectorDimKey      = "vexfs.vector.dimension"
+	vexfsIndexTypeKey      = "vexfs.index.type"
+	vexfsDistanceMetricKey = "vexfs.distance.metric"
+)
+
+// Node implements csi.NodeServer for VexFS volumes.
+type Node struct {
+	nodeID       string
+	maxVolumes   int64
+	ephemeral    bool
+	topologyKeys []string
+
+	nscap []*csi.NodeServiceCapability
+}
+
+// New creates a Node service for the given node ID. ephemeral enables
+// support for CSI ephemeral inline volumes created directly on the node.
+// topologyKeys selects which topology segments NodeGetInfo reports; a node
+// only advertises the keys it was explicitly told to (see ValidTopologyKeys).
+func New(nodeID string, maxVolumes int64, ephemeral bool, topologyKeys []string) *Node {
+	n := &Node{nodeID: nodeID, maxVolumes: maxVolumes, ephemeral: ephemeral, topologyKeys: topologyKeys}
+
+	n.addNodeServiceCapabilities([]csi.NodeServiceCapability_RPC_Type{
+		csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
+		csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
+		csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
+	})
+
+	return n
+}
+
+func (n *Node) addNodeServiceCapabilities(rpcs []csi.NodeServiceCapability_RPC_Type) {
+	var nsc []*csi.NodeServiceCapability
+	for _, rpc := range rpcs {
+		klog.Infof("Enabling node service capability: %v", rpc.String())
+		nsc = append(nsc, &csi.NodeServiceCapability{
+			Type: &csi.NodeServiceCapability_Rpc{
+				Rpc: &csi.NodeServiceCapability_RPC{
+					Type: rpc,
+				},
+			},
+		})
+	}
+	n.nscap = nsc
+}
+
+// NodeStageVolume mounts a VexFS volume to a staging path on the node so it
+// can be bind-mounted into one or more pods by NodePublishVolume.
+func (n *Node) NodeStageVolume(ctx context.Context, req *csi.NodeStageVolumeRequest) (*csi.NodeStageVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+
+	stagingPath := req.GetStagingTargetPath()
+	if len(stagingPath) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Staging target path missing in request")
+	}
+
+	if req.GetVolumeCapability() == nil {
+		return nil, status.Error(codes.InvalidArgument, "Volume capability missing in request")
+	}
+
+	volumePath := filepath.Join(volumesDir, volumeID)
+	if _, err := os.Stat(volumePath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, status.Errorf(codes.NotFound, "Volume %s does not exist", volumeID)
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to stat volume %s: %v", volumeID, err)
+	}
+
+	if mounted, err := isMounted(stagingPath); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to check staging path %s: %v", stagingPath, err)
+	} else if mounted {
+		return &csi.NodeStageVolumeResponse{}, nil
+	}
+
+	klog.Infof("Staging VexFS volume %s at %s", volumeID, stagingPath)
+
+	if req.GetVolumeCapability().GetBlock() != nil {
+		devicePath, err := attachLoopDevice(filepath.Join(volumePath, blockImageName))
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to attach loop device for volume %s: %v", volumeID, err)
+		}
+		if err := ensureBlockTarget(stagingPath); err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to create staging path: %v", err)
+		}
+		if err := bindMount(devicePath, stagingPath, false); err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to stage volume %s: %v", volumeID, err)
+		}
+		return &csi.NodeStageVolumeResponse{}, nil
+	}
+
+	if err := os.MkdirAll(stagingPath, 0750); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to create staging path: %v", err)
+	}
+
+	if err := bindMount(volumePath, stagingPath, false); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to stage volume %s: %v", volumeID, err)
+	}
+
+	return &csi.NodeStageVolumeResponse{}, nil
+}
+
+// NodeUnstageVolume unmounts a VexFS volume from its staging path. It
+// detects block-vs-mount from the staging record itself (the live mount
+// table), rather than a separate flag, so both cases clean up uniformly:
+// a loop device bound under the staging path is detached after unmounting.
+func (n *Node) NodeUnstageVolume(ctx context.Context, req *csi.NodeUnstageVolumeRequest) (*csi.NodeUnstageVolumeResponse, error) {
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+
+	stagingPath := req.GetStagingTargetPath()
+	if len(stagingPath) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Staging target path missing in request")
+	}
+
+	klog.Infof("Unstaging VexFS volume %s from %s", req.GetVolumeId(), stagingPath)
+
+	_, device, found, err := findMount(stagingPath)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to inspect staging path %s: %v", stagingPath, err)
+	}
+
+	if err := unmount(stagingPath); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to unstage volume: %v", err)
+	}
+
+	if found && strings.HasPrefix(device, "/dev/loop") {
+		if err := detachLoopDevice(device); err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to detach loop device %s: %v", device, err)
+		}
+	}
+
+	return &csi.NodeUnstageVolumeResponse{}, nil
+}
+
+// NodePublishVolume bind-mounts a staged VexFS volume into a pod's target
+// path. For CSI ephemeral inline volumes (no controller involved), it
+// instead creates the volume on the node from pod-supplied parameters.
+func (n *Node) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+
+	targetPath := req.GetTargetPath()
+	if len(targetPath) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Target path missing in request")
+	}
+
+	if req.GetVolumeContext()[ephemeralContextKey] == "true" {
+		return n.publishEphemeralVolume(req)
+	}
+
+	stagingPath := req.GetStagingTargetPath()
+	if len(stagingPath) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Staging target path missing in request")
+	}
+
+	if mounted, err := isMounted(targetPath); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to check target path %s: %v", targetPath, err)
+	} else if mounted {
+		return &csi.NodePublishVolumeResponse{}, nil
+	}
+
+	if req.GetVolumeCapability().GetBlock() != nil {
+		if err := ensureBlockTarget(targetPath); err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to create target path: %v", err)
+		}
+	} else if err := os.MkdirAll(targetPath, 0750); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to create target path: %v", err)
+	}
+
+	klog.Infof("Publishing VexFS volume %s at %s", req.GetVolumeId(), targetPath)
+
+	if err := bindMount(stagingPath, targetPath, req.GetReadonly()); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to publish volume %s: %v", req.GetVolumeId(), err)
+	}
+
+	return &csi.NodePublishVolumeResponse{}, nil
+}
+
+// publishEphemeralVolume creates a VexFS volume directly on the node from
+// the pod-supplied inline parameters and bind-mounts it straight to the
+// pod's target path, with no controller or staging path involved.
+func (n *Node) publishEphemeralVolume(req *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
+	if !n.ephemeral {
+		return nil, status.Error(codes.FailedPrecondition, "CSI ephemeral inline volumes are disabled; restart the node plugin with --ephemeral")
+	}
+
+	volumeID := req.GetVolumeId()
+	targetPath := req.GetTargetPath()
+	volumePath := filepath.Join(volumesDir, volumeID)
+
+	if err := createEphemeralVolume(volumeID, req.GetVolumeContext()); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to create ephemeral volume %s: %v", volumeID, err)
+	}
+
+	if mounted, err := isMounted(targetPath); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to check target path %s: %v", targetPath, err)
+	} else if mounted {
+		return &csi.NodePublishVolumeResponse{}, nil
+	}
+
+	if err := os.MkdirAll(targetPath, 0750); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to create target path: %v", err)
+	}
+
+	klog.Infof("Publishing ephemeral VexFS volume %s at %s", volumeID, targetPath)
+
+	if err := bindMount(volumePath, targetPath, req.GetReadonly()); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to publish ephemeral volume %s: %v", volumeID, err)
+	}
+
+	return &csi.NodePublishVolumeResponse{}, nil
+}
+
+// createEphemeralVolume creates a VexFS volume directory on the node,
+// pre-configured from inline parameters, and marks it ephemeral so
+// NodeUnpublishVolume knows to tear it down once the pod exits.
+func createEphemeralVolume(volumeID string, parameters map[string]string) error {
+	volumePath := filepath.Join(volumesDir, volumeID)
+	if err := os.MkdirAll(volumePath, 0755); err != nil {
+		return err
+	}
+
+	metadata := fmt.Sprintf(
+		"volume_id=%s\nephemeral=true\ncreated_by=vexfs-csi\nvector_dimension=%s\nindex_type=%s\ndistance_metric=%s\n",
+		volumeID, parameters[vexfsVectorDimKey], parameters[vexfsIndexTypeKey], parameters[vexfsDistanceMetricKey],
+	)
+	return os.WriteFile(filepath.Join(volumePath, "vexfs.meta"), []byte(metadata), 0644)
+}
+
+// isEphemeralVolume reports whether volumeID was created by
+// createEphemeralVolume, by checking its persisted metadata.
+func isEphemeralVolume(volumeID string) bool {
+	data, err := os.ReadFile(filepath.Join(volumesDir, volumeID, "vexfs.meta"))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(data), "ephemeral=true")
+}
+
+// NodeUnpublishVolume unmounts a VexFS volume from a pod's target path,
+// tearing down the volume itself if it was a CSI ephemeral inline volume.
+func (n *Node) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpublishVolumeRequest) (*csi.NodeUnpublishVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+
+	targetPath := req.GetTargetPath()
+	if len(targetPath) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Target path missing in request")
+	}
+
+	klog.Infof("Unpublishing VexFS volume %s from %s", volumeID, targetPath)
+
+	if err := unmount(targetPath); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to unpublish volume: %v", err)
+	}
+
+	if isEphemeralVolume(volumeID) {
+		klog.Infof("Tearing down ephemeral VexFS volume %s", volumeID)
+		if err := os.RemoveAll(filepath.Join(volumesDir, volumeID)); err != nil && !os.IsNotExist(err) {
+			return nil, status.Errorf(codes.Internal, "Failed to tear down ephemeral volume %s: %v", volumeID, err)
+		}
+	}
+
+	return &csi.NodeUnpublishVolumeResponse{}, nil
+}
+
+// NodeExpandVolume grows the filesystem mounted at a volume's path, or
+// re-probes the loop device for a raw block volume, after the controller
+// has expanded the backing store.
+func (n *Node) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandVolumeRequest) (*csi.NodeExpandVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+
+	volumePath := req.GetVolumePath()
+	if len(volumePath) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume path missing in request")
+	}
+
+	var capacityBytes int64
+	if cr := req.GetCapacityRange(); cr != nil {
+		capacityBytes = cr.GetRequiredBytes()
+	}
+
+	klog.Infof("Expanding VexFS volume %s at %s to %d bytes", volumeID, volumePath, capacityBytes)
+
+	fsType, device, _, err := findMount(volumePath)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to find mount for %s: %v", volumePath, err)
+	}
+
+	var resizeCmd *exec.Cmd
+	switch {
+	case strings.HasPrefix(device, "/dev/loop"):
+		// Raw block volume: ControllerExpandVolume already truncated the
+		// backing block.img to the new size; re-probe the loop device so
+		// the kernel picks up the change before kubelet hands the block
+		// device back to the pod.
+		resizeCmd = exec.CommandContext(ctx, "losetup", "-c", device)
+	case fsType == "ext2", fsType == "ext3", fsType == "ext4":
+		resizeCmd = exec.CommandContext(ctx, "resize2fs", device)
+	case fsType == "xfs":
+		resizeCmd = exec.CommandContext(ctx, "xfs_growfs", volumePath)
+	default:
+		// VexFS volumes are backed by a bind-mounted directory rather than a
+		// formatted block device, so there is no filesystem to grow here;
+		// the new size is enforced purely through vexfs.meta.
+		klog.V(4).Infof("No block filesystem mounted at %s (fstype=%q), nothing to resize", volumePath, fsType)
+	}
+
+	if resizeCmd != nil {
+		if out, err := resizeCmd.CombinedOutput(); err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to grow filesystem at %s: %v: %s", volumePath, err, out)
+		}
+	}
+
+	return &csi.NodeExpandVolumeResponse{CapacityBytes: capacityBytes}, nil
+}
+
+// NodeGetCapabilities returns the capabilities of the node service
+func (n *Node) NodeGetCapabilities(ctx context.Context, req *csi.NodeGetCapabilitiesRequest) (*csi.NodeGetCapabilitiesResponse, error) {
+	klog.V(5).Infof("NodeGetCapabilities called")
+	return &csi.NodeGetCapabilitiesResponse{
+		Capabilities: n.nscap,
+	}, nil
+}
+
+// NodeGetInfo returns information about the node, including the topology
+// segments (zone/GPU/memory-tier) enabled via --topology-keys so the
+// scheduler can place vector index workloads on nodes that can host them.
+func (n *Node) NodeGetInfo(ctx context.Context, req *csi.NodeGetInfoRequest) (*csi.NodeGetInfoResponse, error) {
+	klog.V(5).Infof("NodeGetInfo called")
+
+	resp := &csi.NodeGetInfoResponse{
+		NodeId:            n.nodeID,
+		MaxVolumesPerNode: n.maxVolumes,
+	}
+
+	if segments := n.accessibleTopology(); len(segments) > 0 {
+		resp.AccessibleTopology = &csi.Topology{Segments: segments}
+	}
+
+	return resp, nil
+}
+
+// isMounted reports whether path is already a mount point, by checking it
+// against the kernel's live mount table.
+func isMounted(path string) (bool, error) {
+	_, _, found, err := findMount(path)
+	return found, err
+}
+
+// findMount looks up path in /proc/mounts and returns its filesystem type
+// and source device/directory.
+func findMount(path string) (fsType, device string, found bool, err error) {
+	f, err := os.Open("/proc/mounts")
+	if err != nil {
+		return "", "", false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		if fields[1] == path {
+			device, fsType = fields[0], fields[2]
+			found = true
+		}
+	}
+	return fsType, device, found, scanner.Err()
+}
+
+// ensureBlockTarget creates an empty regular file at path, the convention
+// kubelet uses as the bind-mount target for raw block volumes.
+func ensureBlockTarget(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE, 0660)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// attachLoopDevice exposes a sparse image file as a loop block device and
+// returns its path (e.g. /dev/loop0).
+func attachLoopDevice(imagePath string) (string, error) {
+	out, err := exec.Command("losetup", "-f", "--show", imagePath).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%v: %s", err, out)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// detachLoopDevice releases a loop device previously returned by
+// attachLoopDevice.
+func detachLoopDevice(devicePath string) error {
+	if out, err := exec.Command("losetup", "-d", devicePath).CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, out)
+	}
+	return nil
+}
+
+// bindMount bind-mounts src onto dst, optionally read-only.
+func bindMount(src, dst string, readonly bool) error {
+	if out, err := exec.Command("mount", "--bind", src, dst).CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, out)
+	}
+	if readonly {
+		if out, err := exec.Command("mount", "-o", "remount,bind,ro", dst).CombinedOutput(); err != nil {
+			return fmt.Errorf("%v: %s", err, out)
+		}
+	}
+	return nil
+}
+
+// unmount unmounts path, ignoring the case where it is already unmounted.
+func unmount(path string) error {
+	if mounted, err := isMounted(path); err != nil || !mounted {
+		return err
+	}
+	if out, err := exec.Command("umount", path).CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package node
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// withTempVolumesDir points volumesDir at a scratch directory for the
+// duration of the test, so tests don't touch the real /var/lib/vexfs on
+// the machine running them, and returns it.
+func withTempVolumesDir(t *testing.T) string {
+	t.Helper()
+	orig := volumesDir
+	dir := t.TempDir()
+	volumesDir = dir
+	t.Cleanup(func() { volumesDir = orig })
+	return dir
+}
+
+func TestNodeUnpublishVolumeRequiresVolumeID(t *testing.T) {
+	n := New("node-1", 0, false, nil)
+
+	_, err := n.NodeUnpublishVolume(context.Background(), &csi.NodeUnpublishVolumeRequest{TargetPath: "/tmp/whatever"})
+	if err == nil {
+		t.Fatal("NodeUnpublishVolume with no volume ID returned nil error, want InvalidArgument")
+	}
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Fatalf("NodeUnpublishVolume with no volume ID returned code %v, want %v", code, codes.InvalidArgument)
+	}
+}
+
+func TestNodeUnpublishVolumeRequiresTargetPath(t *testing.T) {
+	n := New("node-1", 0, false, nil)
+
+	_, err := n.NodeUnpublishVolume(context.Background(), &csi.NodeUnpublishVolumeRequest{VolumeId: "vol-1"})
+	if err == nil {
+		t.Fatal("NodeUnpublishVolume with no target path returned nil error, want InvalidArgument")
+	}
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Fatalf("NodeUnpublishVolume with no target path returned code %v, want %v", code, codes.InvalidArgument)
+	}
+}
+
+func TestPublishEphemeralVolumeDisabledByDefault(t *testing.T) {
+	n := New("node-1", 0, false, nil)
+
+	_, err := n.NodePublishVolume(context.Background(), &csi.NodePublishVolumeRequest{
+		VolumeId:      "vol-1",
+		TargetPath:    filepath.Join(t.TempDir(), "target"),
+		VolumeContext: map[string]string{ephemeralContextKey: "true"},
+	})
+	if err == nil {
+		t.Fatal("NodePublishVolume for an ephemeral volume with --ephemeral disabled returned nil error, want FailedPrecondition")
+	}
+	if code := status.Code(err); code != codes.FailedPrecondition {
+		t.Fatalf("NodePublishVolume for an ephemeral volume with --ephemeral disabled returned code %v, want %v", code, codes.FailedPrecondition)
+	}
+}
+
+func TestCreateEphemeralVolumeWritesMetadata(t *testing.T) {
+	dir := withTempVolumesDir(t)
+
+	params := map[string]string{
+		vexfsVectorDimKey:      "128",
+		vexfsIndexTypeKey:      "hnsw",
+		vexfsDistanceMetricKey: "cosine",
+	}
+	if err := createEphemeralVolume("vol-1", params); err != nil {
+		t.Fatalf("createEphemeralVolume: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "vol-1", "vexfs.meta"))
+	if err != nil {
+		t.Fatalf("ReadFile vexfs.meta: %v", err)
+	}
+	want := "volume_id=vol-1\nephemeral=true\ncreated_by=vexfs-csi\nvector_dimension=128\nindex_type=hnsw\ndistance_metric=cosine\n"
+	if string(data) != want {
+		t.Fatalf("vexfs.meta = %q, want %q", data, want)
+	}
+
+	if !isEphemeralVolume("vol-1") {
+		t.Fatal("isEphemeralVolume returned false for a volume created by createEphemeralVolume")
+	}
+}
+
+func TestIsEphemeralVolumeFalseForRegularVolume(t *testing.T) {
+	dir := withTempVolumesDir(t)
+
+	volumePath := filepath.Join(dir, "vol-1")
+	if err := os.MkdirAll(volumePath, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(volumePath, "vexfs.meta"), []byte("volume_id=vol-1\naccess_type=mount\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if isEphemeralVolume("vol-1") {
+		t.Fatal("isEphemeralVolume returned true for a volume with no ephemeral=true marker")
+	}
+	if isEphemeralVolume("does-not-exist") {
+		t.Fatal("isEphemeralVolume returned true for a volume with no metadata file")
+	}
+}
+
+func TestEnsureBlockTarget(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "nested", "target")
+
+	if err := ensureBlockTarget(target); err != nil {
+		t.Fatalf("ensureBlockTarget: %v", err)
+	}
+
+	fi, err := os.Stat(target)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if fi.IsDir() {
+		t.Fatal("ensureBlockTarget created a directory, want a regular file")
+	}
+}
+
+func TestFindMountReportsRootFilesystem(t *testing.T) {
+	// "/" is always mounted on any Linux machine, so it's a stable target
+	// for exercising the /proc/mounts parsing without needing an actual
+	// mount/unmount of our own.
+	fsType, device, found, err := findMount("/")
+	if err != nil {
+		t.Fatalf("findMount(\"/\"): %v", err)
+	}
+	if !found {
+		t.Fatal("findMount(\"/\") reported not found")
+	}
+	if fsType == "" || device == "" {
+		t.Fatalf("findMount(\"/\") = fsType=%q device=%q, want both non-empty", fsType, device)
+	}
+}
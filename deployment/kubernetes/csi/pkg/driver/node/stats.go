@@ -0,0 +1,140 @@
+package node
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	indexDirName  = "index"
+	indexFileName = "hnsw.idx"
+	metaFileName  = "vexfs.meta"
+)
+
+// NodeGetVolumeStats returns usage statistics for a published volume, plus
+// VexFS-specific index health carried in VolumeCondition.
+func (n *Node) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVolumeStatsRequest) (*csi.NodeGetVolumeStatsResponse, error) {
+	volumeID := req.GetVolumeId()
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+
+	volumePath := req.GetVolumePath()
+	if len(volumePath) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume path missing in request")
+	}
+
+	if _, err := os.Stat(volumePath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, status.Errorf(codes.NotFound, "Volume path %s does not exist", volumePath)
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to stat volume path %s: %v", volumePath, err)
+	}
+
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(volumePath, &fs); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to statfs %s: %v", volumePath, err)
+	}
+
+	blockSize := int64(fs.Bsize)
+	total := int64(fs.Blocks) * blockSize
+	available := int64(fs.Bavail) * blockSize
+	used := total - int64(fs.Bfree)*blockSize
+
+	inodesTotal := int64(fs.Files)
+	inodesAvailable := int64(fs.Ffree)
+	inodesUsed := inodesTotal - inodesAvailable
+
+	backingPath := filepath.Join(volumesDir, volumeID)
+	meta := readVolumeMetaFields(filepath.Join(backingPath, metaFileName))
+	abnormal, message := indexHealth(backingPath, meta)
+
+	return &csi.NodeGetVolumeStatsResponse{
+		Usage: []*csi.VolumeUsage{
+			{
+				Unit:      csi.VolumeUsage_BYTES,
+				Total:     total,
+				Available: available,
+				Used:      used,
+			},
+			{
+				Unit:      csi.VolumeUsage_INODES,
+				Total:     inodesTotal,
+				Available: inodesAvailable,
+				Used:      inodesUsed,
+			},
+		},
+		VolumeCondition: &csi.VolumeCondition{
+			Abnormal: abnormal,
+			Message:  message,
+		},
+	}, nil
+}
+
+// readVolumeMetaFields parses a vexfs.meta key=value file into a map.
+func readVolumeMetaFields(path string) map[string]string {
+	fields := map[string]string{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fields
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if key, value, ok := strings.Cut(line, "="); ok {
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+// indexHealth reports whether the volume's HNSW/vector index is present and
+// intact, along with a status message summarizing VexFS-specific metrics:
+// indexed vector count, HNSW node count, a rolling-window average query
+// latency, and the last compaction time, all sourced from vexfs.meta.
+func indexHealth(volumePath string, meta map[string]string) (abnormal bool, message string) {
+	summary := fmt.Sprintf(
+		"indexed_vectors=%s hnsw_nodes=%s avg_query_latency_ms=%s last_compaction=%s",
+		orUnknown(meta["indexed_vectors"]), orUnknown(meta["hnsw_nodes"]),
+		orUnknown(meta["avg_query_latency_ms"]), orUnknown(meta["last_compaction"]),
+	)
+
+	indexFile := filepath.Join(volumePath, indexDirName, indexFileName)
+	data, err := os.ReadFile(indexFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Nothing has been indexed into this volume yet (no CreateVolume
+			// path seeds an index placeholder), which is the normal state
+			// for a freshly-provisioned volume, not a fault.
+			if meta["indexed_vectors"] == "" {
+				return false, "index not yet built: " + summary
+			}
+			return true, "index file missing: " + summary
+		}
+		return true, fmt.Sprintf("failed to read index file: %v: %s", err, summary)
+	}
+
+	if want := meta["index_checksum"]; want != "" {
+		got := sha256.Sum256(data)
+		if hex.EncodeToString(got[:]) != want {
+			return true, "index checksum mismatch: " + summary
+		}
+	}
+
+	return false, summary
+}
+
+func orUnknown(v string) string {
+	if v == "" {
+		return "unknown"
+	}
+	return v
+}
@@ -0,0 +1,116 @@
+package node
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+)
+
+func TestIndexHealthNoIndexYetIsHealthy(t *testing.T) {
+	dir := t.TempDir()
+
+	abnormal, message := indexHealth(dir, map[string]string{})
+	if abnormal {
+		t.Fatalf("indexHealth on a freshly-provisioned volume reported abnormal: %s", message)
+	}
+}
+
+func TestIndexHealthMissingFileWithVectorsIsAbnormal(t *testing.T) {
+	dir := t.TempDir()
+
+	abnormal, message := indexHealth(dir, map[string]string{"indexed_vectors": "10"})
+	if !abnormal {
+		t.Fatalf("indexHealth reported healthy for a volume with indexed_vectors set but no index file: %s", message)
+	}
+}
+
+func TestIndexHealthChecksumMismatchIsAbnormal(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, indexDirName), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, indexDirName, indexFileName), []byte("index-data"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	abnormal, message := indexHealth(dir, map[string]string{"indexed_vectors": "10", "index_checksum": "deadbeef"})
+	if !abnormal {
+		t.Fatalf("indexHealth reported healthy despite a checksum mismatch: %s", message)
+	}
+}
+
+func TestIndexHealthMatchingChecksumIsHealthy(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte("index-data")
+	if err := os.MkdirAll(filepath.Join(dir, indexDirName), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, indexDirName, indexFileName), data, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	sum := sha256.Sum256(data)
+
+	abnormal, message := indexHealth(dir, map[string]string{"indexed_vectors": "10", "index_checksum": hex.EncodeToString(sum[:])})
+	if abnormal {
+		t.Fatalf("indexHealth reported abnormal for a matching checksum: %s", message)
+	}
+}
+
+func TestReadVolumeMetaFields(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vexfs.meta")
+	if err := os.WriteFile(path, []byte("volume_id=vol-1\nsize=1024\naccess_type=mount\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	fields := readVolumeMetaFields(path)
+	if fields["volume_id"] != "vol-1" || fields["size"] != "1024" || fields["access_type"] != "mount" {
+		t.Fatalf("readVolumeMetaFields(%q) = %v", path, fields)
+	}
+}
+
+func TestReadVolumeMetaFieldsMissingFile(t *testing.T) {
+	fields := readVolumeMetaFields(filepath.Join(t.TempDir(), "does-not-exist"))
+	if len(fields) != 0 {
+		t.Fatalf("readVolumeMetaFields on a missing file = %v, want empty", fields)
+	}
+}
+
+func TestNodeGetVolumeStatsFreshVolumeIsHealthy(t *testing.T) {
+	dir := withTempVolumesDir(t)
+
+	volumeID := "vol-1"
+	volumePath := filepath.Join(dir, volumeID)
+	if err := os.MkdirAll(volumePath, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	n := New("node-1", 0, false, nil)
+	resp, err := n.NodeGetVolumeStats(context.Background(), &csi.NodeGetVolumeStatsRequest{
+		VolumeId:   volumeID,
+		VolumePath: volumePath,
+	})
+	if err != nil {
+		t.Fatalf("NodeGetVolumeStats: %v", err)
+	}
+	if resp.VolumeCondition.Abnormal {
+		t.Fatalf("NodeGetVolumeStats on a freshly-created volume reported abnormal: %s", resp.VolumeCondition.Message)
+	}
+	if len(resp.Usage) != 2 {
+		t.Fatalf("NodeGetVolumeStats returned %d usage entries, want 2 (bytes + inodes)", len(resp.Usage))
+	}
+}
+
+func TestNodeGetVolumeStatsRequiresVolumePath(t *testing.T) {
+	n := New("node-1", 0, false, nil)
+
+	_, err := n.NodeGetVolumeStats(context.Background(), &csi.NodeGetVolumeStatsRequest{VolumeId: "vol-1"})
+	if err == nil {
+		t.Fatal("NodeGetVolumeStats with no volume path returned nil error, want InvalidArgument")
+	}
+}
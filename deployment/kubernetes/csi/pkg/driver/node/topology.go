@@ -0,0 +1,149 @@
+package node
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// TopologyZoneKey reports the failure domain a node sits in.
+	TopologyZoneKey = "topology.vexfs.csi.k8s.io/zone"
+	// TopologyGPUKey reports whether a node has an NVIDIA GPU attached,
+	// so GPU-accelerated index builds can be scheduled onto it.
+	TopologyGPUKey = "topology.vexfs.csi.k8s.io/gpu"
+	// TopologyMemoryTierKey reports how much RAM a node has, so large
+	// vector indexes can be scheduled onto nodes that can hold them.
+	TopologyMemoryTierKey = "topology.vexfs.csi.k8s.io/memory-tier"
+)
+
+// ValidTopologyKeys are the topology keys NodeGetInfo knows how to
+// populate. Keys outside this set are rejected by --topology-keys.
+var ValidTopologyKeys = []string{TopologyZoneKey, TopologyGPUKey, TopologyMemoryTierKey}
+
+const (
+	memoryTierStandard = "standard"
+	memoryTierLarge    = "large"
+	memoryTierXLarge   = "xlarge"
+
+	largeTierBytes  = 64 << 30  // 64Gi
+	xlargeTierBytes = 256 << 30 // 256Gi
+)
+
+// ParseTopologyKeys splits and validates a --topology-keys flag value
+// (comma-separated, e.g. "topology.vexfs.csi.k8s.io/zone,...") against
+// ValidTopologyKeys. An empty string yields no keys.
+func ParseTopologyKeys(flagValue string) ([]string, error) {
+	if flagValue == "" {
+		return nil, nil
+	}
+
+	var keys []string
+	for _, raw := range strings.Split(flagValue, ",") {
+		key := strings.TrimSpace(raw)
+		if key == "" {
+			continue
+		}
+		valid := false
+		for _, v := range ValidTopologyKeys {
+			if key == v {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown topology key %q, valid keys: %v", key, ValidTopologyKeys)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// accessibleTopology builds the AccessibleTopology segments this node
+// should report, one per configured --topology-keys entry. It returns nil
+// if no topology keys were enabled.
+func (n *Node) accessibleTopology() map[string]string {
+	if len(n.topologyKeys) == 0 {
+		return nil
+	}
+
+	segments := make(map[string]string, len(n.topologyKeys))
+	for _, key := range n.topologyKeys {
+		switch key {
+		case TopologyZoneKey:
+			segments[key] = nodeZone()
+		case TopologyGPUKey:
+			segments[key] = strconv.FormatBool(hasGPU())
+		case TopologyMemoryTierKey:
+			segments[key] = memoryTier()
+		}
+	}
+	return segments
+}
+
+// nodeZone reports the failure domain VexFS was told this node belongs to,
+// read from the VEXFS_NODE_ZONE environment variable; it reports "unknown"
+// when that variable is unset or empty.
+func nodeZone() string {
+	if zone := os.Getenv("VEXFS_NODE_ZONE"); zone != "" {
+		return zone
+	}
+	return "unknown"
+}
+
+// hasGPU reports whether an NVIDIA GPU is visible to this node.
+func hasGPU() bool {
+	entries, err := os.ReadDir("/dev")
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), "nvidia") {
+			return true
+		}
+	}
+	return false
+}
+
+// memoryTier buckets the node's total RAM (from /proc/meminfo) into
+// standard/large/xlarge, used to place memory-hungry vector indexes.
+func memoryTier() string {
+	total, err := memTotalBytes("/proc/meminfo")
+	if err != nil {
+		return memoryTierStandard
+	}
+	switch {
+	case total >= xlargeTierBytes:
+		return memoryTierXLarge
+	case total >= largeTierBytes:
+		return memoryTierLarge
+	default:
+		return memoryTierStandard
+	}
+}
+
+// memTotalBytes parses the MemTotal line out of a /proc/meminfo-formatted
+// file, which reports the value in kB.
+func memTotalBytes(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemTotal:" {
+			continue
+		}
+		kb, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return kb * 1024, nil
+	}
+	return 0, scanner.Err()
+}
@@ -0,0 +1,77 @@
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTopologyKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", flag: "", want: nil},
+		{name: "single key", flag: TopologyZoneKey, want: []string{TopologyZoneKey}},
+		{
+			name: "multiple keys with whitespace",
+			flag: " " + TopologyZoneKey + ", " + TopologyGPUKey,
+			want: []string{TopologyZoneKey, TopologyGPUKey},
+		},
+		{name: "unknown key", flag: "topology.example.com/bogus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTopologyKeys(tt.flag)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseTopologyKeys(%q) = %v, want error", tt.flag, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTopologyKeys(%q) returned unexpected error: %v", tt.flag, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseTopologyKeys(%q) = %v, want %v", tt.flag, got, tt.want)
+			}
+			for i, key := range tt.want {
+				if got[i] != key {
+					t.Fatalf("ParseTopologyKeys(%q) = %v, want %v", tt.flag, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemTotalBytes(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "meminfo")
+	if err := os.WriteFile(path, []byte("MemTotal:       16393216 kB\nMemFree:         1234 kB\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	got, err := memTotalBytes(path)
+	if err != nil {
+		t.Fatalf("memTotalBytes(%q) returned error: %v", path, err)
+	}
+	if want := int64(16393216) * 1024; got != want {
+		t.Fatalf("memTotalBytes(%q) = %d, want %d", path, got, want)
+	}
+
+	if _, err := memTotalBytes(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("memTotalBytes on a missing file returned nil error, want one")
+	}
+
+	noMatch := filepath.Join(dir, "no-memtotal")
+	if err := os.WriteFile(noMatch, []byte("SomeOtherField: 1\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if got, err := memTotalBytes(noMatch); err != nil || got != 0 {
+		t.Fatalf("memTotalBytes(%q) = (%d, %v), want (0, nil)", noMatch, got, err)
+	}
+}
@@ -0,0 +1,20 @@
+// Package volumestore defines the on-disk layout VexFS CSI volumes and
+// snapshots use. It exists so the controller and node packages, which both
+// need to find a volume's directory, share a single definition rather than
+// two copies that can silently drift apart.
+package volumestore
+
+const (
+	// Dir is where each VexFS volume's payload lives, one directory per
+	// volume ID: Dir/<volume-id>/.
+	Dir = "/var/lib/vexfs/volumes"
+
+	// SnapshotsDir is where each VexFS snapshot's payload lives, one
+	// directory per snapshot ID: SnapshotsDir/<snapshot-id>/.
+	SnapshotsDir = "/var/lib/vexfs/snapshots"
+
+	// BlockImageName is the sparse file a raw block volume's payload lives
+	// in, inside its Dir/<id> directory. losetup attaches it as a loop
+	// device on the node.
+	BlockImageName = "block.img"
+)